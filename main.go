@@ -3,17 +3,16 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"os"
 	"runtime"
-	"time"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 )
 
 type Hello struct {
@@ -40,8 +39,9 @@ func (h *CreateHelloRequestDTO) toHello() Hello {
 }
 
 type HelloResponseDTO struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	XMLName xml.Name `json:"-" xml:"hello"`
+	ID      string   `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name"`
 }
 
 func (h Hello) ToHelloResponseDTO() HelloResponseDTO {
@@ -52,20 +52,36 @@ func (h Hello) ToHelloResponseDTO() HelloResponseDTO {
 }
 
 type ErrorResponseDTO struct {
-	Message string `json:"message"`
+	XMLName xml.Name `json:"-" xml:"error"`
+	Message string   `json:"message" xml:"message"`
+}
+
+type HelloListResponseDTO struct {
+	XMLName    xml.Name           `json:"-" xml:"hellos"`
+	Items      []HelloResponseDTO `json:"items" xml:"items>hello"`
+	NextCursor uint64             `json:"next_cursor" xml:"next_cursor"`
+}
+
+// HelloCollectionResponseDTO wraps a bare []HelloResponseDTO with an XML
+// root, for response paths with no cursor to report (search results, the
+// RESTHandler List route). Without a root element, xml.Marshal on a
+// slice emits sibling <hello> elements with nothing enclosing them, which
+// isn't well-formed XML.
+type HelloCollectionResponseDTO struct {
+	XMLName xml.Name           `json:"-" xml:"hellos"`
+	Items   []HelloResponseDTO `json:"items" xml:"items>hello"`
 }
 
 var (
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_URL"), //"localhost:6379"
-		Password: "",                     // no password set
-		DB:       0,                      // use default DB
-	})
+	store    HelloStore
 	Validate = validator.New()
 )
 
 const (
 	HelloKey = "hello:"
+
+	defaultScanCount = 20
+	maxScanCount     = 200
 )
 
 var (
@@ -78,160 +94,190 @@ var (
 )
 
 func main() {
-	setRedis()
+	s, err := NewHelloStore()
+	if err != nil {
+		panic(err)
+	}
+	store = s
+	pingStore()
+
 	slog.SetLogLoggerLevel(slog.LevelDebug)
 	server := http.NewServeMux()
-	server.Handle("GET /hellos/{id}", http.HandlerFunc(getHelloByID))
-	server.Handle("GET /hellos", http.HandlerFunc(getHelloList))
-	server.Handle("POST /hellos", http.HandlerFunc(setHello))
+	server.Handle("GET /hellos/search", withMiddleware(searchHelloList))
+	server.Handle("GET /hellos/{id}", withMiddleware(getHelloByID))
+	server.Handle("GET /hellos", withMiddleware(getHelloList))
+	server.Handle("POST /hellos", withMiddleware(setHello))
+	server.Handle("DELETE /hellos/{id}", withMiddleware(deleteHello))
+	RESTHandler(server, "/v2/hellos", helloService{store: store})
 	slog.Info("Server start at :45000")
 	http.ListenAndServe(":45000", server)
 }
 
-func setRedis() {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+// pingStore health-checks the store at startup when it knows how to, the
+// same way setRedis used to ping the Redis client directly.
+func pingStore() {
+	pinger, ok := store.(interface{ Ping(context.Context) error })
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
 	defer cancel()
-	_, err := rdb.Ping(ctx).Result()
-	if err != nil {
+	if err := pinger.Ping(ctx); err != nil {
 		panic(err)
 	}
 }
 
-func shouldBindJSON(r *http.Request, v interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
-		return err
-	}
-	if err := Validate.Struct(v); err != nil {
-		return err
-	}
-	return nil
-}
-
 func getHelloByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
-	val, err := rdb.Get(r.Context(), HelloKey+id).Result()
+	hello, err := store.Get(r.Context(), id)
 	if err != nil {
-		if err == redis.Nil {
-			responseNotFound(w, errors.Join(ErrCannotGetHello, err, errors.New("id: "+id)))
+		if errors.Is(err, ErrHelloNotFound) {
+			responseNotFound(w, r, err)
 			return
 		}
-		responseInternalServerError(w, err)
+		responseInternalServerError(w, r, err)
 		return
 	}
-	var res Hello
-	err = json.Unmarshal([]byte(val), &res)
-	if err != nil {
-		responseInternalServerError(w, err)
-		return
-	}
-	responseOK(w, res.ToHelloResponseDTO())
+	responseOK(w, r, hello.ToHelloResponseDTO())
 }
 
 func getHelloList(w http.ResponseWriter, r *http.Request) {
-	keys, err := rdb.Keys(r.Context(), HelloKey+"*").Result()
-	if err != nil {
-		if err == redis.Nil {
-			responseNoContent(w)
-			return
-		}
-		responseInternalServerError(w, err)
+	var query Pagination
+	if err := bindRequest(r, &query); err != nil {
+		responseBadRequest(w, r, err)
 		return
 	}
-	var hellos []Hello
-	for _, id := range keys {
-		val, err := rdb.Get(r.Context(), id).Result()
-		if err != nil {
-			responseInternalServerError(w, errors.Join(ErrHelloNotFound, errors.New("id: "+id)))
-			return
-		}
-		var hello Hello
-		err = json.Unmarshal([]byte(val), &hello)
-		if err != nil {
-			responseInternalServerError(w, err)
-			return
-		}
-		hellos = append(hellos, hello)
+	query = query.normalized()
+
+	hellos, nextCursor, err := store.List(r.Context(), query.Cursor, query.Count)
+	if err != nil {
+		responseInternalServerError(w, r, err)
+		return
 	}
-	var helloResponseList []HelloResponseDTO
+	helloResponseList := make([]HelloResponseDTO, 0, len(hellos))
 	for _, hello := range hellos {
 		helloResponseList = append(helloResponseList, hello.ToHelloResponseDTO())
 	}
-	responseOK(w, helloResponseList)
+	responseOK(w, r, HelloListResponseDTO{Items: helloResponseList, NextCursor: nextCursor})
 }
 
 func setHello(w http.ResponseWriter, r *http.Request) {
 	var createHelloRequestDTO CreateHelloRequestDTO
-	if err := shouldBindJSON(r, &createHelloRequestDTO); err != nil {
-		responseBadRequest(w, err)
+	if err := bindRequest(r, &createHelloRequestDTO); err != nil {
+		responseBadRequest(w, r, err)
 		return
 	}
 	hello := createHelloRequestDTO.toHello()
 	hello.ID = uuid.NewString()
 	newHello, err := storeCreateHello(r.Context(), hello)
 	if err != nil {
-		responseInternalServerError(w, err)
+		responseInternalServerError(w, r, err)
 		return
 	}
-	responseOK(w, newHello.ToHelloResponseDTO())
+	responseOK(w, r, newHello.ToHelloResponseDTO())
 }
 
 func storeCreateHello(ctx context.Context, hello Hello) (Hello, error) {
-	helloBin, err := hello.MarshalBinary()
-	if err != nil {
-		return hello, errors.Join(ErrCannotMarshalHelloBinary, err)
+	return store.Create(ctx, hello)
+}
+
+func deleteHello(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrHelloNotFound) {
+			responseNotFound(w, r, err)
+			return
+		}
+		responseInternalServerError(w, r, err)
+		return
 	}
-	err = rdb.Set(ctx, HelloKey+hello.ID, helloBin, 0).Err()
-	if err != nil {
-		return hello, errors.Join(ErrCannotCreateHello, err)
+	responseNoContent(w)
+}
+
+// searchHelloList is only available on the Redis backend: the name index it
+// queries is maintained by RedisHelloStore, not the HelloStore interface.
+func searchHelloList(w http.ResponseWriter, r *http.Request) {
+	redisStore, ok := store.(*RedisHelloStore)
+	if !ok {
+		responseNotImplemented(w, r, errors.New("search requires STORAGE_BACKEND=redis"))
+		return
 	}
-	rs, err := rdb.Get(ctx, HelloKey+hello.ID).Result()
-	if err != nil {
-		return hello, errors.Join(ErrCannotGetHello, err)
+	var query HelloSearchQueryDTO
+	if err := bindRequest(r, &query); err != nil {
+		responseBadRequest(w, r, err)
+		return
 	}
-	var newHello Hello
-	err = json.Unmarshal([]byte(rs), &newHello)
+	limit, offset := query.normalized()
+
+	hellos, err := redisStore.searchHellosByName(r.Context(), query.Q, limit, offset)
 	if err != nil {
-		return newHello, errors.Join(ErrCannotUnmarshalHello, err)
+		responseInternalServerError(w, r, err)
+		return
+	}
+	helloResponseList := make([]HelloResponseDTO, 0, len(hellos))
+	for _, hello := range hellos {
+		helloResponseList = append(helloResponseList, hello.ToHelloResponseDTO())
 	}
-	return newHello, nil
+	responseOK(w, r, HelloCollectionResponseDTO{Items: helloResponseList})
 }
 
-func responseOK(w http.ResponseWriter, i interface{}) {
-	responseJSON, err := json.Marshal(i)
+func responseOK(w http.ResponseWriter, r *http.Request, i interface{}) {
+	contentType, body, err := encodeBody(r, i)
 	if err != nil {
-		responseInternalServerError(w, err)
+		responseInternalServerError(w, r, err)
 		return
 	}
+	w.Header().Add("Content-Type", contentType)
 	w.WriteHeader(http.StatusOK)
-	w.Header().Add("Content-Type", "application/json")
-	w.Write(responseJSON)
+	w.Write(body)
 }
 
-func responseErr(w http.ResponseWriter, statusCode int, err error) {
+func responseErr(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
 	_, file, line, _ := runtime.Caller(2)
-	slog.Error(fmt.Sprint("file: ", file, ":", line, ": ", err.Error()))
+	slog.Error(fmt.Sprint("file: ", file, ":", line, ": ", err.Error()),
+		"request_id", requestIDFromContext(r.Context()))
 	var e ErrorResponseDTO
 	if err != nil {
 		e.Message = err.Error()
 	}
-	resp, _ := json.Marshal(e)
+	contentType, body, encErr := encodeBody(r, e)
+	if encErr != nil {
+		contentType, body = contentTypeJSON, []byte(`{"message":"failed to encode error response"}`)
+	}
+	w.Header().Add("Content-Type", contentType)
 	w.WriteHeader(statusCode)
-	w.Header().Add("Content-Type", "application/json")
-	w.Write(resp)
+	w.Write(body)
+}
+
+// encodeBody picks a response encoding from the request's Accept header,
+// using XML when it names application/xml or text/xml and JSON for
+// anything else, including empty, "*/*" and the form type.
+func encodeBody(r *http.Request, i interface{}) (string, []byte, error) {
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, contentTypeXML) || strings.Contains(accept, contentTypeXML2) {
+		body, err := xml.Marshal(i)
+		return contentTypeXML, body, err
+	}
+	body, err := json.Marshal(i)
+	return contentTypeJSON, body, err
 }
 
-func responseBadRequest(w http.ResponseWriter, err error) {
-	responseErr(w, http.StatusBadRequest, err)
+func responseBadRequest(w http.ResponseWriter, r *http.Request, err error) {
+	responseErr(w, r, http.StatusBadRequest, err)
 }
 
 func responseNoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func responseNotFound(w http.ResponseWriter, err error) {
-	responseErr(w, http.StatusNotFound, err)
+func responseNotFound(w http.ResponseWriter, r *http.Request, err error) {
+	responseErr(w, r, http.StatusNotFound, err)
+}
+
+func responseInternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	responseErr(w, r, http.StatusInternalServerError, err)
 }
 
-func responseInternalServerError(w http.ResponseWriter, err error) {
-	responseErr(w, http.StatusInternalServerError, err)
+func responseNotImplemented(w http.ResponseWriter, r *http.Request, err error) {
+	responseErr(w, r, http.StatusNotImplemented, err)
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultRequestTimeout bounds how long any single request may run,
+// replacing the timeout that used to be hardcoded around the startup
+// Redis ping only.
+const defaultRequestTimeout = 5 * time.Second
+
+type contextKey int
+
+const requestIDContextKey contextKey = iota
+
+// requestIDFromContext returns the request ID assigned by withRequestID, or
+// "" if the handler is running outside that middleware (e.g. in a test).
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// statusRecorder wraps a ResponseWriter to capture the status code and byte
+// count a handler actually wrote, for withLogging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// withMiddleware applies the cross-cutting concerns every handler gets:
+// structured logging around a request ID and panic recovery, a deadline,
+// and a request ID on both the context and the response header.
+func withMiddleware(h http.HandlerFunc) http.Handler {
+	var handler http.Handler = h
+	handler = withTimeout(defaultRequestTimeout, handler)
+	handler = withRecover(handler)
+	handler = withRequestID(handler)
+	handler = withLogging(handler)
+	return handler
+}
+
+// withRequestID assigns a UUID to the request, exposing it on the
+// X-Request-Id response header and in r.Context() for downstream handlers.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.NewString()
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey, id)))
+	})
+}
+
+// withRecover turns a panic in next into a 500 response instead of
+// crashing the server.
+func withRecover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+				responseInternalServerError(w, r, err)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withTimeout bounds the request's context to d, so handlers that pass
+// r.Context() through to the store don't hang past it.
+func withTimeout(d time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withLogging emits one structured slog line per request: method, path,
+// status, bytes written, duration, and the request ID assigned upstream.
+func withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		slog.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration", time.Since(start),
+			"request_id", rec.Header().Get("X-Request-Id"),
+		)
+	})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Pagination is the request shape RESTHandler and getHelloList bind list
+// queries into via bindRequest, from the cursor/count query parameters.
+type Pagination struct {
+	Cursor uint64 `json:"cursor"`
+	Count  int64  `json:"count"`
+}
+
+// normalized clamps Count to maxScanCount; a Count <= 0 is left as-is so
+// each HelloStore's List can apply its own default.
+func (p Pagination) normalized() Pagination {
+	if p.Count > maxScanCount {
+		p.Count = maxScanCount
+	}
+	return p
+}
+
+// HelloService is the typed RPC-style contract a resource implements.
+// RESTHandler registers routes for it by hand; nothing is generated or
+// derived by reflection, and RESTHandler accepts only a HelloService, so a
+// different resource needs its own registrar.
+type HelloService interface {
+	Create(ctx context.Context, req *CreateHelloRequestDTO) (*HelloResponseDTO, error)
+	Get(ctx context.Context, id string) (*HelloResponseDTO, error)
+	List(ctx context.Context, p Pagination) ([]HelloResponseDTO, error)
+}
+
+// RESTHandler wires svc's methods onto mux under prefix: it binds and
+// validates the request for Create (body) and List (query), passes the
+// {id} path value to Get unchecked, calls the method, and encodes the reply through the same responseOK/responseErr
+// path the hand-written handlers use. Modeled on GoVPP's
+// RESTHandler(rpc RPCService).
+func RESTHandler(mux *http.ServeMux, prefix string, svc HelloService) {
+	mux.Handle("POST "+prefix, withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateHelloRequestDTO
+		if err := bindRequest(r, &req); err != nil {
+			responseBadRequest(w, r, err)
+			return
+		}
+		res, err := svc.Create(r.Context(), &req)
+		if err != nil {
+			responseInternalServerError(w, r, err)
+			return
+		}
+		responseOK(w, r, res)
+	}))
+
+	mux.Handle("GET "+prefix+"/{id}", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		res, err := svc.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			if errors.Is(err, ErrHelloNotFound) {
+				responseNotFound(w, r, err)
+				return
+			}
+			responseInternalServerError(w, r, err)
+			return
+		}
+		responseOK(w, r, res)
+	}))
+
+	mux.Handle("GET "+prefix, withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var query Pagination
+		if err := bindRequest(r, &query); err != nil {
+			responseBadRequest(w, r, err)
+			return
+		}
+		items, err := svc.List(r.Context(), query.normalized())
+		if err != nil {
+			responseInternalServerError(w, r, err)
+			return
+		}
+		responseOK(w, r, HelloCollectionResponseDTO{Items: items})
+	}))
+}
+
+// helloService is the HelloService implementation registered in main. It
+// delegates to whichever HelloStore is configured, so the REST layer
+// RESTHandler registers works the same regardless of STORAGE_BACKEND.
+type helloService struct {
+	store HelloStore
+}
+
+func (s helloService) Create(ctx context.Context, req *CreateHelloRequestDTO) (*HelloResponseDTO, error) {
+	hello := req.toHello()
+	hello.ID = uuid.NewString()
+	newHello, err := s.store.Create(ctx, hello)
+	if err != nil {
+		return nil, err
+	}
+	res := newHello.ToHelloResponseDTO()
+	return &res, nil
+}
+
+func (s helloService) Get(ctx context.Context, id string) (*HelloResponseDTO, error) {
+	hello, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res := hello.ToHelloResponseDTO()
+	return &res, nil
+}
+
+func (s helloService) List(ctx context.Context, p Pagination) ([]HelloResponseDTO, error) {
+	hellos, _, err := s.store.List(ctx, p.Cursor, p.Count)
+	if err != nil {
+		return nil, errors.Join(ErrCannotGetHello, err)
+	}
+	out := make([]HelloResponseDTO, 0, len(hellos))
+	for _, hello := range hellos {
+		out = append(out, hello.ToHelloResponseDTO())
+	}
+	return out, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Secondary index for Hello records, kept alongside the primary hello:<id>
+// keys. RediSearch (FT.CREATE/FT.SEARCH) would be the natural choice in a
+// real deployment, but it's an optional Redis module that isn't guaranteed
+// to be loaded, so this falls back to a plain sorted set ordered by name
+// (searchable with ZRANGEBYLEX) plus a reverse hash so a delete can find the
+// member to remove without a second round-trip to the caller. It's only
+// available when STORAGE_BACKEND=redis. The keys deliberately don't start
+// with HelloKey ("hello:"): RedisHelloStore.List SCANs "hello:*", and a
+// shared prefix would pull these index keys into that scan alongside real
+// records, diluting the page yield.
+const (
+	HelloSearchIndexKey   = "helloidx:name"
+	HelloSearchReverseKey = "helloidx:id2name"
+
+	searchMemberSep = "\x00"
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 200
+)
+
+var ErrCannotIndexHello = errors.New("failed to index hello")
+
+// HelloSearchQueryDTO is the request shape searchHelloList binds the
+// q/limit/offset query parameters into via bindRequest.
+type HelloSearchQueryDTO struct {
+	Q      string `json:"q" validate:"required"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// normalized defaults Limit to defaultSearchLimit and clamps Limit to
+// [1, maxSearchLimit] and Offset to [0, maxSearchLimit], so a zero/negative/
+// oversized query param can't reach ZRangeByLex and turn into a 500.
+func (q HelloSearchQueryDTO) normalized() (limit, offset int) {
+	limit = q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	offset = q.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > maxSearchLimit {
+		offset = maxSearchLimit
+	}
+	return limit, offset
+}
+
+func searchIndexMember(hello Hello) string {
+	return hello.Name + searchMemberSep + hello.ID
+}
+
+// indexHello writes the hello record and its index entries atomically: a
+// plain SET for the record, a ZADD for the name-ordered index, and an HSET
+// on the reverse hash so deindexHello can clean up later without knowing the
+// name in advance.
+func (s *RedisHelloStore) indexHello(ctx context.Context, hello Hello) (Hello, error) {
+	helloBin, err := hello.MarshalBinary()
+	if err != nil {
+		return hello, errors.Join(ErrCannotMarshalHelloBinary, err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, HelloKey+hello.ID, helloBin, 0)
+		pipe.ZAdd(ctx, HelloSearchIndexKey, redis.Z{Score: 0, Member: searchIndexMember(hello)})
+		pipe.HSet(ctx, HelloSearchReverseKey, hello.ID, hello.Name)
+		return nil
+	})
+	if err != nil {
+		return hello, errors.Join(ErrCannotIndexHello, err)
+	}
+
+	rs, err := s.client.Get(ctx, HelloKey+hello.ID).Result()
+	if err != nil {
+		return hello, errors.Join(ErrCannotGetHello, err)
+	}
+	var newHello Hello
+	if err := json.Unmarshal([]byte(rs), &newHello); err != nil {
+		return newHello, errors.Join(ErrCannotUnmarshalHello, err)
+	}
+	return newHello, nil
+}
+
+// deindexHello removes a hello record and its index entries atomically. The
+// name needed to compute the sorted-set member is looked up from the reverse
+// hash rather than requiring the caller to supply it.
+func (s *RedisHelloStore) deindexHello(ctx context.Context, id string) error {
+	name, err := s.client.HGet(ctx, HelloSearchReverseKey, id).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return errors.Join(ErrHelloNotFound, errors.New("id: "+id))
+		}
+		return errors.Join(ErrCannotGetHello, err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, HelloKey+id)
+		pipe.ZRem(ctx, HelloSearchIndexKey, name+searchMemberSep+id)
+		pipe.HDel(ctx, HelloSearchReverseKey, id)
+		return nil
+	})
+	if err != nil {
+		return errors.Join(ErrCannotIndexHello, err)
+	}
+	return nil
+}
+
+// searchHellosByName returns hellos whose name starts with q, ordered
+// lexicographically, using a ZRANGEBYLEX prefix scan over the name index.
+func (s *RedisHelloStore) searchHellosByName(ctx context.Context, q string, limit, offset int) ([]Hello, error) {
+	min := "[" + q
+	max := "[" + q + "\xff"
+	members, err := s.client.ZRangeByLex(ctx, HelloSearchIndexKey, &redis.ZRangeBy{
+		Min:    min,
+		Max:    max,
+		Offset: int64(offset),
+		Count:  int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, errors.Join(ErrCannotGetHello, err)
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		_, id, ok := strings.Cut(member, searchMemberSep)
+		if !ok {
+			continue
+		}
+		ids = append(ids, HelloKey+id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	vals, err := s.client.MGet(ctx, ids...).Result()
+	if err != nil {
+		return nil, errors.Join(ErrCannotGetHello, err)
+	}
+	hellos := make([]Hello, 0, len(vals))
+	for _, v := range vals {
+		if v == nil {
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var hello Hello
+		if err := json.Unmarshal([]byte(str), &hello); err != nil {
+			return nil, errors.Join(ErrCannotUnmarshalHello, err)
+		}
+		hellos = append(hellos, hello)
+	}
+	return hellos, nil
+}
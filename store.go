@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// HelloStore is the storage abstraction every Hello handler goes through.
+// It replaces the package-level rdb client so the service can run against
+// Redis, an in-memory map (for tests), or a SQL database, selected by the
+// STORAGE_BACKEND env var.
+type HelloStore interface {
+	Get(ctx context.Context, id string) (Hello, error)
+	List(ctx context.Context, cursor uint64, n int64) ([]Hello, uint64, error)
+	Create(ctx context.Context, hello Hello) (Hello, error)
+	Delete(ctx context.Context, id string) error
+}
+
+const (
+	backendRedis  = "redis"
+	backendMemory = "memory"
+	backendSQL    = "sql"
+)
+
+// NewHelloStore builds the HelloStore selected by STORAGE_BACKEND,
+// defaulting to Redis to match the service's original behavior.
+func NewHelloStore() (HelloStore, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", backendRedis:
+		return NewRedisHelloStore(os.Getenv("REDIS_URL")), nil
+	case backendMemory:
+		return NewMemoryHelloStore(), nil
+	case backendSQL:
+		return NewSQLHelloStoreFromEnv()
+	default:
+		return nil, errors.New("unknown STORAGE_BACKEND: " + backend)
+	}
+}
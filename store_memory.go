@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MemoryHelloStore is an in-process HelloStore backed by a map, used in
+// tests and when STORAGE_BACKEND=memory so the service is testable without
+// Docker. Its List cursor is the index into a stable, ID-sorted ordering
+// rather than a real Redis SCAN cursor, but it honors the same
+// cursor/next_cursor contract.
+type MemoryHelloStore struct {
+	mu     sync.RWMutex
+	hellos map[string]Hello
+}
+
+func NewMemoryHelloStore() *MemoryHelloStore {
+	return &MemoryHelloStore{hellos: make(map[string]Hello)}
+}
+
+func (s *MemoryHelloStore) Get(ctx context.Context, id string) (Hello, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hello, ok := s.hellos[id]
+	if !ok {
+		return Hello{}, errors.Join(ErrHelloNotFound, errors.New("id: "+id))
+	}
+	return hello, nil
+}
+
+func (s *MemoryHelloStore) List(ctx context.Context, cursor uint64, n int64) ([]Hello, uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if n <= 0 {
+		n = defaultScanCount
+	}
+
+	ids := make([]string, 0, len(s.hellos))
+	for id := range s.hellos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	start := int(cursor)
+	if start > len(ids) {
+		start = len(ids)
+	}
+	end := start + int(n)
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	page := make([]Hello, 0, end-start)
+	for _, id := range ids[start:end] {
+		page = append(page, s.hellos[id])
+	}
+	next := uint64(end)
+	if end >= len(ids) {
+		next = 0
+	}
+	return page, next, nil
+}
+
+func (s *MemoryHelloStore) Create(ctx context.Context, hello Hello) (Hello, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hellos[hello.ID] = hello
+	return hello, nil
+}
+
+func (s *MemoryHelloStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.hellos[id]; !ok {
+		return errors.Join(ErrHelloNotFound, errors.New("id: "+id))
+	}
+	delete(s.hellos, id)
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHelloStore is the original Redis-backed HelloStore. It also owns the
+// name-ordered secondary index (see search.go) so search and delete can
+// maintain it alongside the record.
+type RedisHelloStore struct {
+	client *redis.Client
+}
+
+func NewRedisHelloStore(addr string) *RedisHelloStore {
+	return &RedisHelloStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr, //"localhost:6379"
+			Password: "",   // no password set
+			DB:       0,    // use default DB
+		}),
+	}
+}
+
+func (s *RedisHelloStore) Ping(ctx context.Context) error {
+	_, err := s.client.Ping(ctx).Result()
+	return err
+}
+
+func (s *RedisHelloStore) Get(ctx context.Context, id string) (Hello, error) {
+	val, err := s.client.Get(ctx, HelloKey+id).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return Hello{}, errors.Join(ErrHelloNotFound, err, errors.New("id: "+id))
+		}
+		return Hello{}, errors.Join(ErrCannotGetHello, err)
+	}
+	var hello Hello
+	if err := json.Unmarshal([]byte(val), &hello); err != nil {
+		return Hello{}, errors.Join(ErrCannotUnmarshalHello, err)
+	}
+	return hello, nil
+}
+
+func (s *RedisHelloStore) List(ctx context.Context, cursor uint64, n int64) ([]Hello, uint64, error) {
+	if n <= 0 {
+		n = defaultScanCount
+	}
+	keys, nextCursor, err := s.client.Scan(ctx, cursor, HelloKey+"*", n).Result()
+	if err != nil {
+		return nil, 0, errors.Join(ErrCannotGetHello, err)
+	}
+	if len(keys) == 0 {
+		return []Hello{}, nextCursor, nil
+	}
+
+	vals, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, 0, errors.Join(ErrCannotGetHello, err)
+	}
+	hellos := make([]Hello, 0, len(vals))
+	for _, v := range vals {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var hello Hello
+		if err := json.Unmarshal([]byte(str), &hello); err != nil {
+			return nil, 0, errors.Join(ErrCannotUnmarshalHello, err)
+		}
+		hellos = append(hellos, hello)
+	}
+	return hellos, nextCursor, nil
+}
+
+func (s *RedisHelloStore) Create(ctx context.Context, hello Hello) (Hello, error) {
+	return s.indexHello(ctx, hello)
+}
+
+func (s *RedisHelloStore) Delete(ctx context.Context, id string) error {
+	return s.deindexHello(ctx, id)
+}
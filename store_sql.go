@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+
+	_ "github.com/lib/pq"
+)
+
+// SQLHelloStore is a database/sql-backed HelloStore, in the spirit of
+// swapping Redis for a relational client. DATABASE_DRIVER only selects
+// which registered database/sql driver opens DATABASE_DSN (for connecting
+// to a differently-hosted Postgres, say); the queries below use Postgres's
+// $1/$2 placeholder syntax, so a non-Postgres driver isn't actually
+// supported without rewriting them.
+type SQLHelloStore struct {
+	db *sql.DB
+}
+
+func NewSQLHelloStoreFromEnv() (*SQLHelloStore, error) {
+	driver := os.Getenv("DATABASE_DRIVER")
+	if driver == "" {
+		driver = "postgres"
+	}
+	db, err := sql.Open(driver, os.Getenv("DATABASE_DSN"))
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to open sql store"), err)
+	}
+	store := &SQLHelloStore{db: db}
+	if err := store.migrate(context.Background()); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *SQLHelloStore) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS hellos (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+	return err
+}
+
+func (s *SQLHelloStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLHelloStore) Get(ctx context.Context, id string) (Hello, error) {
+	var hello Hello
+	err := s.db.QueryRowContext(ctx, `SELECT id, name FROM hellos WHERE id = $1`, id).Scan(&hello.ID, &hello.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Hello{}, errors.Join(ErrHelloNotFound, errors.New("id: "+id))
+		}
+		return Hello{}, errors.Join(ErrCannotGetHello, err)
+	}
+	return hello, nil
+}
+
+func (s *SQLHelloStore) List(ctx context.Context, cursor uint64, n int64) ([]Hello, uint64, error) {
+	if n <= 0 {
+		n = defaultScanCount
+	}
+	// database/sql's default value converter rejects a uint64 with the high
+	// bit set, so the cursor (really just an OFFSET) is passed as int64.
+	offset := int64(cursor)
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM hellos ORDER BY id LIMIT $1 OFFSET $2`, n, offset)
+	if err != nil {
+		return nil, 0, errors.Join(ErrCannotGetHello, err)
+	}
+	defer rows.Close()
+
+	var hellos []Hello
+	for rows.Next() {
+		var hello Hello
+		if err := rows.Scan(&hello.ID, &hello.Name); err != nil {
+			return nil, 0, errors.Join(ErrCannotUnmarshalHello, err)
+		}
+		hellos = append(hellos, hello)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.Join(ErrCannotGetHello, err)
+	}
+
+	next := uint64(offset) + uint64(len(hellos))
+	if int64(len(hellos)) < n {
+		next = 0
+	}
+	return hellos, next, nil
+}
+
+func (s *SQLHelloStore) Create(ctx context.Context, hello Hello) (Hello, error) {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO hellos (id, name) VALUES ($1, $2)`, hello.ID, hello.Name)
+	if err != nil {
+		return Hello{}, errors.Join(ErrCannotCreateHello, err)
+	}
+	return hello, nil
+}
+
+func (s *SQLHelloStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM hellos WHERE id = $1`, id)
+	if err != nil {
+		return errors.Join(ErrCannotGetHello, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Join(ErrCannotGetHello, err)
+	}
+	if affected == 0 {
+		return errors.Join(ErrHelloNotFound, errors.New("id: "+id))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestHelloStoreConformance runs the same behavioral contract against every
+// HelloStore implementation, so MemoryHelloStore's synthetic cursor and
+// SQLHelloStore's offset-as-cursor are held to the same List/Get/Create/
+// Delete semantics as RedisHelloStore's real SCAN cursor. Redis and SQL are
+// skipped when their backing server isn't reachable, since this repo has no
+// way to spin one up in CI.
+func TestHelloStoreConformance(t *testing.T) {
+	backends := map[string]func(t *testing.T) HelloStore{
+		"memory": func(t *testing.T) HelloStore {
+			return NewMemoryHelloStore()
+		},
+		"redis": func(t *testing.T) HelloStore {
+			addr := os.Getenv("REDIS_URL")
+			if addr == "" {
+				t.Skip("REDIS_URL not set")
+			}
+			store := NewRedisHelloStore(addr)
+			if err := store.Ping(context.Background()); err != nil {
+				t.Skipf("redis unavailable: %v", err)
+			}
+			return store
+		},
+		"sql": func(t *testing.T) HelloStore {
+			if os.Getenv("DATABASE_DSN") == "" {
+				t.Skip("DATABASE_DSN not set")
+			}
+			store, err := NewSQLHelloStoreFromEnv()
+			if err != nil {
+				t.Skipf("sql store unavailable: %v", err)
+			}
+			return store
+		},
+	}
+
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			assertHelloStoreConformance(t, newStore(t))
+		})
+	}
+}
+
+func assertHelloStoreConformance(t *testing.T, store HelloStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	hello := Hello{ID: uuid.NewString(), Name: "conformance"}
+
+	created, err := store.Create(ctx, hello)
+	if err != nil {
+		t.Fatalf("Create(%+v): %v", hello, err)
+	}
+	if created != hello {
+		t.Fatalf("Create(%+v) = %+v, want %+v", hello, created, hello)
+	}
+
+	got, err := store.Get(ctx, hello.ID)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", hello.ID, err)
+	}
+	if got != hello {
+		t.Fatalf("Get(%q) = %+v, want %+v", hello.ID, got, hello)
+	}
+
+	if !helloStoreContains(t, ctx, store, hello) {
+		t.Fatalf("List did not contain %+v after Create", hello)
+	}
+
+	if err := store.Delete(ctx, hello.ID); err != nil {
+		t.Fatalf("Delete(%q): %v", hello.ID, err)
+	}
+
+	if _, err := store.Get(ctx, hello.ID); !errors.Is(err, ErrHelloNotFound) {
+		t.Fatalf("Get(%q) after Delete = %v, want ErrHelloNotFound", hello.ID, err)
+	}
+
+	if err := store.Delete(ctx, hello.ID); !errors.Is(err, ErrHelloNotFound) {
+		t.Fatalf("Delete(%q) of missing id = %v, want ErrHelloNotFound", hello.ID, err)
+	}
+}
+
+// helloStoreContains pages through List with a small count until it finds
+// want or the cursor returns to 0, exercising the cursor/next_cursor
+// contract rather than assuming a single page covers everything.
+func helloStoreContains(t *testing.T, ctx context.Context, store HelloStore, want Hello) bool {
+	t.Helper()
+	var cursor uint64
+	for page := 0; page < 1000; page++ {
+		items, next, err := store.List(ctx, cursor, 50)
+		if err != nil {
+			t.Fatalf("List(%d, 50): %v", cursor, err)
+		}
+		for _, item := range items {
+			if item == want {
+				return true
+			}
+		}
+		if next == 0 {
+			return false
+		}
+		cursor = next
+	}
+	t.Fatalf("List did not terminate (cursor never returned to 0)")
+	return false
+}